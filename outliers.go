@@ -0,0 +1,138 @@
+package roundedcensus
+
+import (
+	"math/big"
+	"sort"
+)
+
+// OutlierDetector removes outliers from a census before grouping. Kept and
+// outliers are returned separately so the outliers can be appended back to
+// the final rounded census untouched, as GroupAndRoundCensus does.
+type OutlierDetector interface {
+	Detect(participants []*Participant) (kept, outliers []*Participant)
+}
+
+// ZScoreDetector discards participants whose balance's z-score (the number
+// of standard deviations from the mean) exceeds Threshold.
+type ZScoreDetector struct {
+	Threshold float64
+}
+
+// Detect implements OutlierDetector.
+func (d ZScoreDetector) Detect(participants []*Participant) ([]*Participant, []*Participant) {
+	return zScore(participants, d.Threshold)
+}
+
+// IQRDetector discards participants whose balance falls outside
+// [Q1 - K*IQR, Q3 + K*IQR], where Q1 and Q3 are the first and third
+// quartiles of the balances and IQR = Q3 - Q1.
+type IQRDetector struct {
+	K float64
+}
+
+// Detect implements OutlierDetector.
+func (d IQRDetector) Detect(participants []*Participant) ([]*Participant, []*Participant) {
+	if len(participants) == 0 {
+		return nil, nil
+	}
+	sorted := make([]*Participant, len(participants))
+	copy(sorted, participants)
+	sort.Sort(ByBalance(sorted))
+
+	q1 := percentileBalance(sorted, 25)
+	q3 := percentileBalance(sorted, 75)
+	iqr := new(big.Float).Sub(q3, q1)
+	k := big.NewFloat(d.K)
+	lowerBound := new(big.Float).Sub(q1, new(big.Float).Mul(iqr, k))
+	upperBound := new(big.Float).Add(q3, new(big.Float).Mul(iqr, k))
+
+	kept := make([]*Participant, 0, len(participants))
+	outliers := make([]*Participant, 0)
+	for _, p := range participants {
+		balance := new(big.Float).SetInt(p.Balance)
+		if balance.Cmp(lowerBound) < 0 || balance.Cmp(upperBound) > 0 {
+			outliers = append(outliers, p)
+		} else {
+			kept = append(kept, p)
+		}
+	}
+	return kept, outliers
+}
+
+// MADDetector discards participants whose median absolute deviation score,
+// |x - median| / MAD, exceeds Threshold. It is far more robust than
+// ZScoreDetector for the heavy-tailed token-balance distributions this
+// module targets, since neither the median nor the MAD is skewed by the
+// handful of whale balances that would otherwise inflate the mean and
+// standard deviation. When the MAD is zero (at least half the balances equal
+// the median), no participant is discarded.
+type MADDetector struct {
+	Threshold float64
+}
+
+// Detect implements OutlierDetector.
+func (d MADDetector) Detect(participants []*Participant) ([]*Participant, []*Participant) {
+	if len(participants) == 0 {
+		return nil, nil
+	}
+	sorted := make([]*Participant, len(participants))
+	copy(sorted, participants)
+	sort.Sort(ByBalance(sorted))
+
+	median := percentileBalance(sorted, 50)
+
+	deviations := make([]*big.Float, len(sorted))
+	for i, p := range sorted {
+		balance := new(big.Float).SetInt(p.Balance)
+		deviations[i] = new(big.Float).Abs(new(big.Float).Sub(balance, median))
+	}
+	sort.Slice(deviations, func(i, j int) bool { return deviations[i].Cmp(deviations[j]) < 0 })
+	mad := percentileFloat(deviations, 50)
+
+	outliers := make([]*Participant, 0)
+	if mad.Sign() == 0 {
+		return participants, outliers
+	}
+
+	kept := make([]*Participant, 0, len(participants))
+	for _, p := range participants {
+		balance := new(big.Float).SetInt(p.Balance)
+		deviation := new(big.Float).Abs(new(big.Float).Sub(balance, median))
+		score := new(big.Float).Quo(deviation, mad)
+		if score.Cmp(big.NewFloat(d.Threshold)) > 0 {
+			outliers = append(outliers, p)
+		} else {
+			kept = append(kept, p)
+		}
+	}
+	return kept, outliers
+}
+
+// percentileBalance returns the balance at the given percentile (0-100) of a
+// slice of participants already sorted by ByBalance, using linear
+// interpolation between the two closest ranks.
+func percentileBalance(sorted []*Participant, percentile float64) *big.Float {
+	balances := make([]*big.Float, len(sorted))
+	for i, p := range sorted {
+		balances[i] = new(big.Float).SetInt(p.Balance)
+	}
+	return percentileFloat(balances, percentile)
+}
+
+// percentileFloat returns the value at the given percentile (0-100) of a
+// slice of values already sorted in ascending order, using linear
+// interpolation between the two closest ranks.
+func percentileFloat(sorted []*big.Float, percentile float64) *big.Float {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := (percentile / 100) * float64(len(sorted)-1)
+	lowerIndex := int(rank)
+	upperIndex := lowerIndex + 1
+	if upperIndex >= len(sorted) {
+		return sorted[lowerIndex]
+	}
+	fraction := big.NewFloat(rank - float64(lowerIndex))
+	diff := new(big.Float).Sub(sorted[upperIndex], sorted[lowerIndex])
+	return new(big.Float).Add(sorted[lowerIndex], new(big.Float).Mul(diff, fraction))
+}
@@ -0,0 +1,84 @@
+package roundedcensus
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// generateParetoCensus generates a synthetic census whose balances follow a
+// Pareto distribution (a handful of very large "whale" balances and a long
+// tail of small ones), which is representative of real token-holder
+// distributions.
+func generateParetoCensus(size int, xm, alpha float64) []*Participant {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	participants := make([]*Participant, size)
+	for i := 0; i < size; i++ {
+		u := r.Float64()
+		balance := xm / math.Pow(1-u, 1/alpha)
+		participants[i] = &Participant{
+			Address: fmt.Sprintf("addr%d", i),
+			Balance: big.NewInt(int64(balance)),
+		}
+	}
+	return participants
+}
+
+// TestOutlierDetectors checks that, for each detector, the number of kept
+// participants plus the number of outliers equals the size of the census.
+func TestOutlierDetectors(t *testing.T) {
+	census := generateParetoCensus(2000, 100, 1.5)
+	detectors := map[string]OutlierDetector{
+		"zscore": ZScoreDetector{Threshold: 2.0},
+		"iqr":    IQRDetector{K: 1.5},
+		"mad":    MADDetector{Threshold: 3.0},
+	}
+	for name, detector := range detectors {
+		kept, outliers := detector.Detect(census)
+		if len(kept)+len(outliers) != len(census) {
+			t.Fatalf("%s: expected %d participants total, got %d kept + %d outliers", name, len(census), len(kept), len(outliers))
+		}
+	}
+}
+
+// BenchmarkOutlierDetectors measures the accuracy/threshold curve of each
+// detector on a synthetic Pareto-distributed census: the census is rounded
+// end to end through GroupAndRoundCensus with each detector plugged in, and
+// the resulting accuracy is reported alongside the fraction of the census
+// each detector discarded as outliers. This is where a handful of whale
+// balances inflate the standard deviation and cause ZScoreDetector to keep
+// almost everyone, which shows up here as a lower accuracy than IQR or MAD
+// achieve at a comparable threshold.
+func BenchmarkOutlierDetectors(b *testing.B) {
+	census := generateParetoCensus(5000, 100, 1.2)
+	benchmarks := []struct {
+		name     string
+		detector OutlierDetector
+	}{
+		{"ZScore/threshold=2.0", ZScoreDetector{Threshold: 2.0}},
+		{"ZScore/threshold=3.0", ZScoreDetector{Threshold: 3.0}},
+		{"IQR/k=1.5", IQRDetector{K: 1.5}},
+		{"IQR/k=3.0", IQRDetector{K: 3.0}},
+		{"MAD/threshold=2.5", MADDetector{Threshold: 2.5}},
+		{"MAD/threshold=3.5", MADDetector{Threshold: 3.5}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			config := DefaultGroupsConfig
+			config.Detector = bm.detector
+
+			_, outliers := bm.detector.Detect(census)
+			outlierPct := 100 * float64(len(outliers)) / float64(len(census))
+
+			var accuracy float64
+			for i := 0; i < b.N; i++ {
+				_, accuracy, _ = GroupAndRoundCensus(census, config)
+			}
+			b.ReportMetric(accuracy, "%accuracy")
+			b.ReportMetric(outlierPct, "%outliers")
+		})
+	}
+}
@@ -18,7 +18,10 @@ The main steps of the algorithm are:
      equal to the groupBalanceDiff threshold.
 
 3. Round Group Balances:
-   - Each group's balances are rounded down to the lowest value within that group.
+   - Each group's balances are rounded down to the lowest value within that group,
+     unless GroupsConfig.Mode selects a different rounding strategy (see
+     ModeDifferentialPrivacy), or GroupsConfig.Strategy is GroupByKMeansPP,
+     whose groups are rounded to their centroid by default (see roundGroups).
 
 4. Output Rounded Balances and Accuracy:
    - The algorithm provides the new list of participants with their rounded balances
@@ -27,7 +30,9 @@ The main steps of the algorithm are:
 
 import (
 	"fmt"
+	"math"
 	"math/big"
+	"math/rand"
 	"sort"
 )
 
@@ -44,22 +49,147 @@ func (a ByBalance) Len() int           { return len(a) }
 func (a ByBalance) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a ByBalance) Less(i, j int) bool { return a[i].Balance.Cmp(a[j].Balance) < 0 }
 
-// roundGroups rounds the balances within each group to the lowest value in the group.
-func roundGroups(groups [][]*Participant) []*Participant {
+// RoundingMode selects how roundGroups computes the rounded balance assigned
+// to every participant within a group.
+type RoundingMode int
+
+const (
+	// ModeLowestCommonDigit rounds each group to the value produced by
+	// roundToFirstCommonDigit. This is the original rounding strategy. With
+	// GroupByKMeansPP it is treated as ModeClusterCentroid (see roundGroups).
+	ModeLowestCommonDigit RoundingMode = iota
+	// ModeDifferentialPrivacy replaces the group value with a differentially
+	// private noisy mean, see dpGroupValue.
+	ModeDifferentialPrivacy
+	// ModeClusterCentroid rounds each group to the mean balance of its
+	// members. This is the natural rounding strategy for a cluster produced
+	// by GroupByKMeansPP, whose centers are themselves balance means.
+	ModeClusterCentroid
+	// ModeClusterMin rounds each group to its smallest member balance.
+	ModeClusterMin
+)
+
+// GroupsConfig bundles the parameters that control grouping, rounding and
+// outlier removal in GroupAndRoundCensus.
+type GroupsConfig struct {
+	// MinPrivacyThreshold is the minimum number of participants that must
+	// share a rounded balance.
+	MinPrivacyThreshold int64
+	// GroupBalanceDiff is the maximum balance difference allowed between
+	// consecutive participants for them to be grouped together.
+	GroupBalanceDiff *big.Int
+	// MinAccuracy is the minimum acceptable accuracy of the rounded census.
+	MinAccuracy float64
+	// OutliersThreshold is the z-score threshold used to detect outliers
+	// when Detector is nil.
+	OutliersThreshold float64
+	// Detector selects the outlier detection strategy applied before
+	// grouping. When nil, a ZScoreDetector using OutliersThreshold is used.
+	Detector OutlierDetector
+	// Mode selects the rounding strategy applied to each group.
+	Mode RoundingMode
+	// Epsilon is the total differential privacy budget spent per group when
+	// Mode is ModeDifferentialPrivacy. It is split evenly between the noisy
+	// sum and the noisy count used to recover the group mean.
+	Epsilon float64
+	// Delta is the failure probability of the Gaussian mechanism. When zero,
+	// the Laplace mechanism is used instead.
+	Delta float64
+	// LowerBound and UpperBound clamp balances before noise is added, which
+	// bounds the sensitivity of the differentially private aggregate. Only
+	// used when Mode is ModeDifferentialPrivacy.
+	LowerBound *big.Int
+	UpperBound *big.Int
+	// Strategy selects how participants are partitioned into groups.
+	Strategy GroupingStrategy
+	// KMeansK is the number of clusters used by GroupByKMeansPP. When zero,
+	// it is derived from len(participants)/MinPrivacyThreshold.
+	KMeansK int
+	// KMeansIterations is the number of Lloyd refinement iterations run
+	// after k-means++ seeding. When zero, a sane default is used.
+	KMeansIterations int
+}
+
+// DefaultGroupsConfig is the GroupsConfig used when no explicit
+// configuration is provided.
+var DefaultGroupsConfig = GroupsConfig{
+	MinPrivacyThreshold: 10,
+	GroupBalanceDiff:    big.NewInt(10),
+	MinAccuracy:         80,
+	OutliersThreshold:   2.0,
+	Mode:                ModeLowestCommonDigit,
+	Strategy:            GroupBySortedThreshold,
+}
+
+// GroupingStrategy selects how participants are partitioned into groups
+// before rounding.
+type GroupingStrategy int
+
+const (
+	// GroupBySortedThreshold sorts participants by balance and buckets them
+	// into groups of at least MinPrivacyThreshold members, extending a group
+	// while consecutive balances stay within GroupBalanceDiff of each other.
+	// The last group holds whatever participants remain and may be smaller.
+	GroupBySortedThreshold GroupingStrategy = iota
+	// GroupByKMeansPP clusters participants by balance using k-means++
+	// seeding followed by a few Lloyd iterations, avoiding the full sort and
+	// repeated threshold sweep that GroupBySortedThreshold relies on.
+	GroupByKMeansPP
+)
+
+// roundGroups rounds the balances within each group according to
+// config.Mode. A GroupByKMeansPP cluster whose Mode was left at its zero
+// value defaults to ModeClusterCentroid instead of ModeLowestCommonDigit,
+// since the cluster's centroid (not its lowest common digit) is what
+// GroupByKMeansPP actually optimizes for.
+func roundGroups(groups [][]*Participant, config GroupsConfig) []*Participant {
 	roundedCensus := []*Participant{}
 	for _, group := range groups {
 		if len(group) == 0 {
 			continue
 		}
-		lowestBalance := roundToFirstCommonDigit(group)
-		// lowestBalance := group[0].Balance
+		var groupBalance *big.Int
+		switch {
+		case config.Mode == ModeDifferentialPrivacy:
+			groupBalance = dpGroupValue(group, config)
+		case config.Mode == ModeClusterCentroid:
+			groupBalance = clusterCentroid(group)
+		case config.Mode == ModeClusterMin:
+			groupBalance = clusterMin(group)
+		case config.Strategy == GroupByKMeansPP && config.Mode == ModeLowestCommonDigit:
+			groupBalance = clusterCentroid(group)
+		default:
+			groupBalance = roundToFirstCommonDigit(group)
+		}
 		for _, participant := range group {
-			roundedCensus = append(roundedCensus, &Participant{Address: participant.Address, Balance: lowestBalance})
+			roundedCensus = append(roundedCensus, &Participant{Address: participant.Address, Balance: groupBalance})
 		}
 	}
 	return roundedCensus
 }
 
+// clusterCentroid returns the mean balance of a group, used as the rounded
+// value for ModeClusterCentroid.
+func clusterCentroid(group []*Participant) *big.Int {
+	sum := new(big.Int)
+	for _, p := range group {
+		sum.Add(sum, p.Balance)
+	}
+	return sum.Div(sum, big.NewInt(int64(len(group))))
+}
+
+// clusterMin returns the smallest balance in a group, used as the rounded
+// value for ModeClusterMin.
+func clusterMin(group []*Participant) *big.Int {
+	min := group[0].Balance
+	for _, p := range group[1:] {
+		if p.Balance.Cmp(min) < 0 {
+			min = p.Balance
+		}
+	}
+	return min
+}
+
 // calculateAccuracy computes the accuracy of the rounding process.
 func calculateAccuracy(original, rounded []*Participant) float64 {
 	var totalOriginal, totalRounded big.Int
@@ -73,8 +203,22 @@ func calculateAccuracy(original, rounded []*Participant) float64 {
 	return 100 - (accuracy * 100)
 }
 
-// groupAndRoundCensus groups the cleanedParticipants and rounds their balances.
-func groupAndRoundCensus(participants []*Participant, privacyThreshold int, groupBalanceDiff *big.Int) ([]*Participant, float64) {
+// groupParticipants partitions participants into groups according to
+// config.Strategy.
+func groupParticipants(participants []*Participant, privacyThreshold int64, config GroupsConfig) [][]*Participant {
+	switch config.Strategy {
+	case GroupByKMeansPP:
+		return kmeansGroupParticipants(participants, privacyThreshold, config)
+	default:
+		return sortedThresholdGroupParticipants(participants, privacyThreshold, config.GroupBalanceDiff)
+	}
+}
+
+// sortedThresholdGroupParticipants sorts participants by balance and buckets
+// them into groups of at least privacyThreshold members, extending a group
+// while consecutive balances differ by no more than groupBalanceDiff. The
+// last group holds whatever participants remain and may be smaller.
+func sortedThresholdGroupParticipants(participants []*Participant, privacyThreshold int64, groupBalanceDiff *big.Int) [][]*Participant {
 	sort.Sort(ByBalance(participants))
 	var groups [][]*Participant
 	var currentGroup []*Participant
@@ -85,7 +229,7 @@ func groupAndRoundCensus(participants []*Participant, privacyThreshold int, grou
 			lastParticipant := currentGroup[len(currentGroup)-1]
 			balanceDiff := new(big.Int).Abs(new(big.Int).Sub(participant.Balance, lastParticipant.Balance))
 
-			if len(currentGroup) < privacyThreshold || balanceDiff.Cmp(groupBalanceDiff) <= 0 {
+			if int64(len(currentGroup)) < privacyThreshold || balanceDiff.Cmp(groupBalanceDiff) <= 0 {
 				currentGroup = append(currentGroup, participant)
 			} else {
 				groups = append(groups, currentGroup)
@@ -97,41 +241,238 @@ func groupAndRoundCensus(participants []*Participant, privacyThreshold int, grou
 			groups = append(groups, currentGroup)
 		}
 	}
-	roundedCensus := roundGroups(groups)
-	accuracy := calculateAccuracy(participants, roundedCensus)
-	return roundedCensus, accuracy
+	return groups
+}
+
+// kmeansGroupParticipants partitions participants into groups using 1-D
+// k-means++ clustering on their balances instead of sorting the whole
+// census. It seeds k cluster centers with the k-means++ rule (each new
+// center chosen with probability proportional to its squared distance to the
+// nearest already-chosen center), refines them with a few Lloyd iterations,
+// and finally merges any cluster smaller than privacyThreshold into its
+// nearest neighbor so every returned group still satisfies the privacy
+// threshold.
+func kmeansGroupParticipants(participants []*Participant, privacyThreshold int64, config GroupsConfig) [][]*Participant {
+	n := len(participants)
+	if n == 0 {
+		return nil
+	}
+
+	k := config.KMeansK
+	if k <= 0 {
+		k = n / int(privacyThreshold)
+	}
+	if k < 1 {
+		k = 1
+	}
+	if k > n {
+		k = n
+	}
+
+	balances := make([]float64, n)
+	for i, p := range participants {
+		balances[i], _ = new(big.Float).SetInt(p.Balance).Float64()
+	}
+
+	centers := kmeansPPSeed(balances, k)
+
+	iterations := config.KMeansIterations
+	if iterations <= 0 {
+		iterations = 10
+	}
+	assignments := make([]int, n)
+	for iter := 0; iter < iterations; iter++ {
+		changed := false
+		for i, balance := range balances {
+			nearest := nearestCenter(balance, centers)
+			if assignments[i] != nearest {
+				assignments[i] = nearest
+				changed = true
+			}
+		}
+		sums := make([]float64, len(centers))
+		counts := make([]int, len(centers))
+		for i, balance := range balances {
+			sums[assignments[i]] += balance
+			counts[assignments[i]]++
+		}
+		for c := range centers {
+			if counts[c] > 0 {
+				centers[c] = sums[c] / float64(counts[c])
+			}
+		}
+		if !changed && iter > 0 {
+			break
+		}
+	}
+
+	clusters := make([][]*Participant, len(centers))
+	for i, participant := range participants {
+		clusters[assignments[i]] = append(clusters[assignments[i]], participant)
+	}
+
+	return mergeSmallClusters(clusters, centers, privacyThreshold)
+}
+
+// kmeansPPSeed picks k initial cluster centers from values using k-means++:
+// the first center is chosen uniformly at random, and each subsequent center
+// is sampled from the remaining points with probability proportional to its
+// squared distance to the nearest already-chosen center.
+func kmeansPPSeed(values []float64, k int) []float64 {
+	centers := make([]float64, 0, k)
+	centers = append(centers, values[rand.Intn(len(values))])
+
+	d2 := make([]float64, len(values))
+	for i, v := range values {
+		diff := v - centers[0]
+		d2[i] = diff * diff
+	}
+
+	for len(centers) < k {
+		cumulative := make([]float64, len(values))
+		var total float64
+		for i, d := range d2 {
+			total += d
+			cumulative[i] = total
+		}
+		var nextCenter float64
+		if total == 0 {
+			nextCenter = values[rand.Intn(len(values))]
+		} else {
+			target := rand.Float64() * total
+			idx := sort.Search(len(cumulative), func(i int) bool { return cumulative[i] >= target })
+			if idx >= len(values) {
+				idx = len(values) - 1
+			}
+			nextCenter = values[idx]
+		}
+		centers = append(centers, nextCenter)
+
+		for i, v := range values {
+			diff := v - nextCenter
+			if d := diff * diff; d < d2[i] {
+				d2[i] = d
+			}
+		}
+	}
+	return centers
+}
+
+// nearestCenter returns the index of the center closest to value.
+func nearestCenter(value float64, centers []float64) int {
+	nearest := 0
+	best := math.Abs(value - centers[0])
+	for i, c := range centers[1:] {
+		if d := math.Abs(value - c); d < best {
+			best = d
+			nearest = i + 1
+		}
+	}
+	return nearest
+}
+
+// mergeSmallClusters folds any cluster with fewer than privacyThreshold
+// members into its nearest neighboring cluster (by centroid distance) so
+// that every returned group satisfies the minimum privacy threshold.
+func mergeSmallClusters(clusters [][]*Participant, centers []float64, privacyThreshold int64) [][]*Participant {
+	merged := true
+	for merged {
+		merged = false
+		for i, cluster := range clusters {
+			if len(clusters) <= 1 || int64(len(cluster)) >= privacyThreshold {
+				continue
+			}
+			nearest := -1
+			best := math.MaxFloat64
+			for j := range clusters {
+				if j == i {
+					continue
+				}
+				if d := math.Abs(centers[i] - centers[j]); d < best {
+					best = d
+					nearest = j
+				}
+			}
+			if nearest == -1 {
+				continue
+			}
+			clusters[nearest] = append(clusters[nearest], cluster...)
+			var sum float64
+			for _, p := range clusters[nearest] {
+				f, _ := new(big.Float).SetInt(p.Balance).Float64()
+				sum += f
+			}
+			centers[nearest] = sum / float64(len(clusters[nearest]))
+			clusters = append(clusters[:i], clusters[i+1:]...)
+			centers = append(centers[:i], centers[i+1:]...)
+			merged = true
+			break
+		}
+	}
+	return clusters
+}
+
+// groupAndRoundCensus groups the cleanedParticipants and rounds their
+// balances using the default (lowest common digit, sorted-threshold)
+// strategy.
+func groupAndRoundCensus(participants []*Participant, privacyThreshold int64, groupBalanceDiff *big.Int) []*Participant {
+	config := DefaultGroupsConfig
+	config.GroupBalanceDiff = groupBalanceDiff
+	groups := groupParticipants(participants, privacyThreshold, config)
+	return roundGroups(groups, config)
 }
 
 // GroupAndRoundCensus groups the participants and rounds their balances. It
 // rounds the balances of the participants with the highest accuracy possible
 // while maintaining a minimum privacy threshold. It discards outliers from the
 // rounding process but returns them in the final list of participants.
-func GroupAndRoundCensus(participants []*Participant, minPrivacyThreshold int, groupBalanceDiff *big.Int, minAccuracy float64) ([]*Participant, float64, int, error) {
-	// cleanedParticipants, outliers := detectLowerOutliers(participants, 5.0)
-	cleanedParticipants, outliers := zScore(participants, 2.0)
-
-	maxPrivacyThreshold := len(participants) / minPrivacyThreshold
-	currentPrivacyThreshold := minPrivacyThreshold
-	maxAccuracy := 0.0
-	maxAccuracyPrivacyThreshold := currentPrivacyThreshold
-	for currentPrivacyThreshold <= maxPrivacyThreshold {
-		_, lastAccuracy := groupAndRoundCensus(cleanedParticipants, currentPrivacyThreshold, groupBalanceDiff)
-		if lastAccuracy > maxAccuracy {
-			maxAccuracy = lastAccuracy
-			maxAccuracyPrivacyThreshold = currentPrivacyThreshold
-		}
-		gap := currentPrivacyThreshold / 33
-		if gap < 1 {
-			gap = 1
-		}
-		currentPrivacyThreshold += gap
-	}
-	roundCensus, finalAccuracy := groupAndRoundCensus(cleanedParticipants, maxAccuracyPrivacyThreshold, groupBalanceDiff)
+func GroupAndRoundCensus(participants []*Participant, config GroupsConfig) ([]*Participant, float64, error) {
+	detector := config.Detector
+	if detector == nil {
+		detector = ZScoreDetector{Threshold: config.OutliersThreshold}
+	}
+	cleanedParticipants, outliers := detector.Detect(participants)
+
+	var roundCensus []*Participant
+	var finalAccuracy float64
+	if config.Strategy == GroupByKMeansPP {
+		// GroupByKMeansPP already derives its cluster count k from
+		// config.KMeansK (or len(participants)/MinPrivacyThreshold), so there
+		// is no privacy threshold to sweep here: doing so would re-run
+		// k-means++ seeding and Lloyd iterations at every step, which is
+		// strictly more expensive than the sorted-threshold scan it was
+		// meant to replace, and nondeterministic between iterations.
+		groups := groupParticipants(cleanedParticipants, config.MinPrivacyThreshold, config)
+		roundCensus = roundGroups(groups, config)
+		finalAccuracy = calculateAccuracy(cleanedParticipants, roundCensus)
+	} else {
+		maxPrivacyThreshold := int64(len(participants)) / config.MinPrivacyThreshold
+		currentPrivacyThreshold := config.MinPrivacyThreshold
+		maxAccuracy := 0.0
+		maxAccuracyPrivacyThreshold := currentPrivacyThreshold
+		for currentPrivacyThreshold <= maxPrivacyThreshold {
+			groups := groupParticipants(cleanedParticipants, currentPrivacyThreshold, config)
+			lastAccuracy := calculateAccuracy(cleanedParticipants, roundGroups(groups, config))
+			if lastAccuracy > maxAccuracy {
+				maxAccuracy = lastAccuracy
+				maxAccuracyPrivacyThreshold = currentPrivacyThreshold
+			}
+			gap := currentPrivacyThreshold / 33
+			if gap < 1 {
+				gap = 1
+			}
+			currentPrivacyThreshold += gap
+		}
+		groups := groupParticipants(cleanedParticipants, maxAccuracyPrivacyThreshold, config)
+		roundCensus = roundGroups(groups, config)
+		finalAccuracy = calculateAccuracy(cleanedParticipants, roundCensus)
+	}
+
 	roundCensus = append(roundCensus, outliers...)
-	if finalAccuracy < minAccuracy {
-		return roundCensus, finalAccuracy, maxAccuracyPrivacyThreshold, fmt.Errorf("could not find a privacy threshold that satisfies the minimum accuracy")
+	if finalAccuracy < config.MinAccuracy {
+		return roundCensus, finalAccuracy, fmt.Errorf("could not find a privacy threshold that satisfies the minimum accuracy")
 	}
-	return roundCensus, finalAccuracy, maxAccuracyPrivacyThreshold, nil
+	return roundCensus, finalAccuracy, nil
 }
 
 // zScore identifies and returns outliers based on a specified z-score
@@ -238,3 +579,94 @@ func roundToFirstCommonDigit(participants []*Participant) *big.Int {
 	}
 	return minBalance
 }
+
+// dpGroupValue computes a differentially private aggregate for the balances
+// in a group using the Laplace mechanism, or the Gaussian mechanism when
+// config.Delta is set. Balances are clamped to [config.LowerBound,
+// config.UpperBound] so that the sensitivity of the sum is finite. The total
+// privacy budget (config.Epsilon) is split evenly between the noisy sum and
+// the noisy count used to recover the group mean.
+func dpGroupValue(group []*Participant, config GroupsConfig) *big.Int {
+	lower := new(big.Float).SetInt(config.LowerBound)
+	upper := new(big.Float).SetInt(config.UpperBound)
+	midpoint := new(big.Float).Quo(new(big.Float).Add(lower, upper), big.NewFloat(2))
+	rangeWidth, _ := new(big.Float).Sub(upper, lower).Float64()
+
+	sum := 0.0
+	for _, p := range group {
+		balance := new(big.Float).SetInt(p.Balance)
+		if balance.Cmp(lower) < 0 {
+			balance = lower
+		} else if balance.Cmp(upper) > 0 {
+			balance = upper
+		}
+		normalized, _ := new(big.Float).Sub(balance, midpoint).Float64()
+		sum += normalized
+	}
+
+	// The total budget config.Epsilon (and, for the Gaussian mechanism,
+	// config.Delta) is split evenly between the sum and count releases, so
+	// each mechanism only gets to spend sumEpsilon/sumDelta (resp.
+	// countEpsilon/countDelta) = config.Epsilon/2 (config.Delta/2). The
+	// Laplace mechanism's scale is sensitivity/allocated_epsilon, so
+	// plugging the per-mechanism budget into that formula is what turns
+	// (Upper-Lower)/(2*epsilon) into rangeWidth/(2*sumEpsilon) below (same
+	// reasoning for 1/countEpsilon on the count), rather than using
+	// config.Epsilon directly, which would double the epsilon actually
+	// spent once both releases are composed. Splitting delta the same way
+	// keeps the composed (epsilon, delta) guarantee at the documented even
+	// split instead of silently doubling delta too.
+	//
+	// The sensitivity of the sum is rangeWidth/2, not rangeWidth: balances
+	// are normalized by subtracting the midpoint before summing, so a
+	// single changed record can move the sum by at most
+	// |Upper - midpoint| = (Upper-Lower)/2 in either direction.
+	sumEpsilon := config.Epsilon / 2
+	countEpsilon := config.Epsilon / 2
+	sumDelta := config.Delta / 2
+	countDelta := config.Delta / 2
+
+	var noisySum, noisyCount float64
+	if config.Delta > 0 {
+		noisySum = sum + sampleGaussianNoise(rangeWidth/2, sumEpsilon, sumDelta)
+		noisyCount = float64(len(group)) + sampleGaussianNoise(1, countEpsilon, countDelta)
+	} else {
+		noisySum = sum + sampleLaplaceNoise(rangeWidth/(2*sumEpsilon))
+		noisyCount = float64(len(group)) + sampleLaplaceNoise(1/countEpsilon)
+	}
+
+	return finalizeDPMean(noisySum, noisyCount, midpoint)
+}
+
+// finalizeDPMean recovers a differentially private group mean from a noisy
+// sum and a noisy count, re-adding the midpoint that was subtracted before
+// noise was added. A non-positive noisy count has no sensible reciprocal, so
+// it falls back to the group midpoint instead of dividing by zero or
+// flipping the sign of the mean.
+func finalizeDPMean(noisySum, noisyCount float64, midpoint *big.Float) *big.Int {
+	if noisyCount <= 0 {
+		mean, _ := midpoint.Int(nil)
+		return mean
+	}
+	mean := new(big.Float).Add(big.NewFloat(noisySum/noisyCount), midpoint)
+	rounded, _ := mean.Int(nil)
+	return rounded
+}
+
+// sampleLaplaceNoise draws a sample from a zero-centered Laplace
+// distribution with the given scale, using inverse transform sampling.
+func sampleLaplaceNoise(scale float64) float64 {
+	u := rand.Float64() - 0.5
+	if u < 0 {
+		return scale * math.Log(1+2*u)
+	}
+	return -scale * math.Log(1-2*u)
+}
+
+// sampleGaussianNoise draws a sample from a zero-centered Gaussian
+// distribution calibrated to the (epsilon, delta) Gaussian mechanism for the
+// given sensitivity.
+func sampleGaussianNoise(sensitivity, epsilon, delta float64) float64 {
+	sigma := (sensitivity / epsilon) * math.Sqrt(2*math.Log(1.25/delta))
+	return rand.NormFloat64() * sigma
+}
@@ -3,6 +3,7 @@ package roundedcensus
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
 	"math/rand"
 	"os"
@@ -154,3 +155,144 @@ func TestAutoRoundingAlgorithm(t *testing.T) {
 	t.Logf("Final Accuracy: %.2f%%, Number of Groups: %d, Final Holders: %d\n",
 		accuracy, len(groupsCounters), len(census))
 }
+
+// TestDifferentialPrivacyConvergence checks that, at a large privacy budget
+// (epsilon), the average of repeated noisy group means produced by
+// dpGroupValue is close to the true group mean.
+func TestDifferentialPrivacyConvergence(t *testing.T) {
+	group := []*Participant{
+		{Address: "a", Balance: big.NewInt(100)},
+		{Address: "b", Balance: big.NewInt(120)},
+		{Address: "c", Balance: big.NewInt(90)},
+		{Address: "d", Balance: big.NewInt(110)},
+	}
+	var trueSum int64
+	for _, p := range group {
+		trueSum += p.Balance.Int64()
+	}
+	trueMean := float64(trueSum) / float64(len(group))
+
+	config := GroupsConfig{
+		Mode:       ModeDifferentialPrivacy,
+		Epsilon:    10000,
+		LowerBound: big.NewInt(0),
+		UpperBound: big.NewInt(200),
+	}
+
+	const runs = 500
+	var total float64
+	for i := 0; i < runs; i++ {
+		total += float64(dpGroupValue(group, config).Int64())
+	}
+	avg := total / runs
+	if diff := math.Abs(avg - trueMean); diff > 5 {
+		t.Fatalf("expected average noisy mean close to %.2f at high epsilon, got %.2f", trueMean, avg)
+	}
+}
+
+// TestDifferentialPrivacyFallback checks that a non-positive noisy count
+// falls back to the group midpoint instead of dividing by zero.
+func TestDifferentialPrivacyFallback(t *testing.T) {
+	midpoint := big.NewFloat(100)
+	mean := finalizeDPMean(42, 0, midpoint)
+	expected, _ := midpoint.Int(nil)
+	if mean.Cmp(expected) != 0 {
+		t.Fatalf("expected fallback to midpoint %s, got %s", expected.String(), mean.String())
+	}
+	mean = finalizeDPMean(42, -3, midpoint)
+	if mean.Cmp(expected) != 0 {
+		t.Fatalf("expected fallback to midpoint %s, got %s", expected.String(), mean.String())
+	}
+}
+
+// TestKMeansGrouping checks that GroupByKMeansPP produces clusters that all
+// satisfy the minimum privacy threshold (mergeSmallClusters enforces this),
+// and that the sort-based strategy remains available side by side with it.
+// Unlike GroupByKMeansPP, GroupBySortedThreshold offers no such guarantee:
+// its trailing group is appended unconditionally regardless of size, so it
+// is only checked here for being non-empty and covering every participant.
+func TestKMeansGrouping(t *testing.T) {
+	census := generateRandomCensus(2000, 1000000)
+	privacyThreshold := int64(20)
+
+	config := DefaultGroupsConfig
+	config.Strategy = GroupByKMeansPP
+	config.MinPrivacyThreshold = privacyThreshold
+	kmeansGroups := groupParticipants(census, privacyThreshold, config)
+	for _, group := range kmeansGroups {
+		if int64(len(group)) < privacyThreshold {
+			t.Fatalf("cluster of size %d does not satisfy the minimum privacy threshold of %d", len(group), privacyThreshold)
+		}
+	}
+
+	config.Strategy = GroupBySortedThreshold
+	sortedGroups := groupParticipants(census, privacyThreshold, config)
+	var sortedTotal int
+	for _, group := range sortedGroups {
+		if len(group) == 0 {
+			t.Fatalf("sorted strategy produced an empty group")
+		}
+		sortedTotal += len(group)
+	}
+	if sortedTotal != len(census) {
+		t.Fatalf("expected sorted strategy to cover all %d participants, got %d", len(census), sortedTotal)
+	}
+}
+
+// TestKMeansRoundsToCentroid checks that roundGroups rounds a GroupByKMeansPP
+// cluster to the mean of its (unrounded) members' balances rather than
+// falling back to ModeLowestCommonDigit.
+func TestKMeansRoundsToCentroid(t *testing.T) {
+	census := generateRandomCensus(2000, 1000000)
+	threshold := int64(20)
+
+	config := DefaultGroupsConfig
+	config.Strategy = GroupByKMeansPP
+	config.MinPrivacyThreshold = threshold
+
+	clusters := groupParticipants(census, threshold, config)
+	rounded := roundGroups(clusters, config)
+
+	idx := 0
+	for _, cluster := range clusters {
+		sum := new(big.Int)
+		for _, p := range cluster {
+			sum.Add(sum, p.Balance)
+		}
+		expectedCentroid := new(big.Int).Div(sum, big.NewInt(int64(len(cluster))))
+		for range cluster {
+			if rounded[idx].Balance.Cmp(expectedCentroid) != 0 {
+				t.Fatalf("expected cluster rounded balance %s to equal its centroid %s", rounded[idx].Balance.String(), expectedCentroid.String())
+			}
+			idx++
+		}
+	}
+}
+
+// TestGroupAndRoundCensusKMeansSingleCluster checks that GroupAndRoundCensus
+// honours an explicit KMeansK for GroupByKMeansPP: with a single cluster
+// (k=1) and a privacy threshold lower than the census size, every
+// participant ends up in the same group and shares one rounded balance.
+func TestGroupAndRoundCensusKMeansSingleCluster(t *testing.T) {
+	census := generateRandomCensus(200, 1000000)
+
+	config := DefaultGroupsConfig
+	config.Strategy = GroupByKMeansPP
+	config.MinPrivacyThreshold = 10
+	config.KMeansK = 1
+	config.MinAccuracy = 0
+	config.OutliersThreshold = 1e9 // keep every participant, including whales
+
+	rounded, _, err := GroupAndRoundCensus(census, config)
+	if err != nil {
+		t.Fatalf("Error rounding census: %v", err)
+	}
+
+	balances := map[string]bool{}
+	for _, p := range rounded {
+		balances[p.Balance.String()] = true
+	}
+	if len(balances) != 1 {
+		t.Fatalf("expected a single k=1 cluster to produce one rounded balance, got %d", len(balances))
+	}
+}
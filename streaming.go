@@ -0,0 +1,148 @@
+package roundedcensus
+
+import (
+	"math/big"
+	"sort"
+)
+
+// histogramBin is a single bin of a StreamingRounder's approximate
+// histogram. It tracks the running count and sum of the balances assigned to
+// it, and the addresses of its members.
+type histogramBin struct {
+	Count   int
+	Sum     *big.Int
+	Members []string
+}
+
+// mean returns the bin's mean balance.
+func (b *histogramBin) mean() *big.Int {
+	if b.Count == 0 {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Div(b.Sum, big.NewInt(int64(b.Count)))
+}
+
+// StreamingRounder builds a rounded census incrementally, one participant at
+// a time, using a BigML-style approximate histogram instead of sorting the
+// full census. This lets callers round censuses that don't fit in memory, or
+// that arrive from a live token-transfer stream, without ever calling
+// sort.Sort on the full set of participants.
+type StreamingRounder struct {
+	// MaxBins is the maximum number of histogram bins kept at any time.
+	MaxBins int
+	bins    []*histogramBin
+}
+
+// NewStreamingRounder creates a StreamingRounder that keeps at most maxBins
+// histogram bins.
+func NewStreamingRounder(maxBins int) *StreamingRounder {
+	return &StreamingRounder{MaxBins: maxBins}
+}
+
+// Add inserts a participant's balance into the histogram, placing it in an
+// existing bin whose mean equals the balance, or creating a new singleton
+// bin. Whenever the number of bins exceeds MaxBins, the two adjacent bins
+// (sorted by mean) with the smallest mean difference are merged.
+func (s *StreamingRounder) Add(addr string, balance *big.Int) {
+	for _, bin := range s.bins {
+		if bin.mean().Cmp(balance) == 0 {
+			bin.Count++
+			bin.Sum.Add(bin.Sum, balance)
+			bin.Members = append(bin.Members, addr)
+			return
+		}
+	}
+
+	s.bins = append(s.bins, &histogramBin{
+		Count:   1,
+		Sum:     new(big.Int).Set(balance),
+		Members: []string{addr},
+	})
+	s.sortBins()
+
+	if s.MaxBins > 0 && len(s.bins) > s.MaxBins {
+		s.mergeClosestBins()
+	}
+}
+
+// sortBins keeps the histogram's bins ordered by mean, which mergeClosestBins
+// and Finalize rely on to only ever compare adjacent bins.
+func (s *StreamingRounder) sortBins() {
+	sort.Slice(s.bins, func(i, j int) bool {
+		return s.bins[i].mean().Cmp(s.bins[j].mean()) < 0
+	})
+}
+
+// mergeClosestBins merges the two adjacent bins with the smallest mean
+// difference into one, reducing the histogram by a single bin.
+func (s *StreamingRounder) mergeClosestBins() {
+	if len(s.bins) < 2 {
+		return
+	}
+	closest := 0
+	smallestDiff := new(big.Int).Abs(new(big.Int).Sub(s.bins[1].mean(), s.bins[0].mean()))
+	for i := 1; i < len(s.bins)-1; i++ {
+		diff := new(big.Int).Abs(new(big.Int).Sub(s.bins[i+1].mean(), s.bins[i].mean()))
+		if diff.Cmp(smallestDiff) < 0 {
+			smallestDiff = diff
+			closest = i
+		}
+	}
+	s.mergeBinAt(closest)
+}
+
+// mergeBinAt merges the bin at index i with the bin at index i+1.
+func (s *StreamingRounder) mergeBinAt(i int) {
+	a, b := s.bins[i], s.bins[i+1]
+	a.Count += b.Count
+	a.Sum.Add(a.Sum, b.Sum)
+	a.Members = append(a.Members, b.Members...)
+	s.bins = append(s.bins[:i+1], s.bins[i+2:]...)
+}
+
+// Finalize merges any bin whose count is below minPrivacyThreshold into its
+// nearest neighbor (by mean), then returns the resulting participants with
+// each member's balance set to its bin's mean.
+func (s *StreamingRounder) Finalize(minPrivacyThreshold int) []*Participant {
+	s.sortBins()
+	for len(s.bins) > 1 {
+		small := -1
+		for i, bin := range s.bins {
+			if bin.Count < minPrivacyThreshold {
+				small = i
+				break
+			}
+		}
+		if small == -1 {
+			break
+		}
+
+		neighbor := small - 1
+		switch {
+		case small == 0:
+			neighbor = small + 1
+		case small == len(s.bins)-1:
+			neighbor = small - 1
+		default:
+			leftDiff := new(big.Int).Abs(new(big.Int).Sub(s.bins[small].mean(), s.bins[small-1].mean()))
+			rightDiff := new(big.Int).Abs(new(big.Int).Sub(s.bins[small+1].mean(), s.bins[small].mean()))
+			if rightDiff.Cmp(leftDiff) < 0 {
+				neighbor = small + 1
+			}
+		}
+		if neighbor < small {
+			s.mergeBinAt(neighbor)
+		} else {
+			s.mergeBinAt(small)
+		}
+	}
+
+	participants := make([]*Participant, 0)
+	for _, bin := range s.bins {
+		mean := bin.mean()
+		for _, addr := range bin.Members {
+			participants = append(participants, &Participant{Address: addr, Balance: mean})
+		}
+	}
+	return participants
+}
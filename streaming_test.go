@@ -0,0 +1,46 @@
+package roundedcensus
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestStreamingRounderBinLimit checks that the number of histogram bins never
+// exceeds MaxBins, and that every participant added ends up in the finalized
+// census exactly once.
+func TestStreamingRounderBinLimit(t *testing.T) {
+	rounder := NewStreamingRounder(5)
+	census := generateRandomCensus(500, 1000000)
+	for _, p := range census {
+		rounder.Add(p.Address, p.Balance)
+		if len(rounder.bins) > rounder.MaxBins {
+			t.Fatalf("histogram grew to %d bins, exceeding MaxBins=%d", len(rounder.bins), rounder.MaxBins)
+		}
+	}
+
+	rounded := rounder.Finalize(1)
+	if len(rounded) != len(census) {
+		t.Fatalf("expected %d participants in the finalized census, got %d", len(census), len(rounded))
+	}
+}
+
+// TestStreamingRounderMinPrivacyThreshold checks that Finalize merges away
+// any bin whose count is below the requested privacy threshold.
+func TestStreamingRounderMinPrivacyThreshold(t *testing.T) {
+	rounder := NewStreamingRounder(100)
+	balances := []int64{10, 10, 10, 20, 30, 30, 30, 30}
+	for i, balance := range balances {
+		rounder.Add(string(rune('a'+i)), big.NewInt(balance))
+	}
+
+	rounded := rounder.Finalize(3)
+	groups := map[string]int{}
+	for _, p := range rounded {
+		groups[p.Balance.String()]++
+	}
+	for balance, count := range groups {
+		if count < 3 {
+			t.Fatalf("group with balance %s has only %d members, below the minimum privacy threshold", balance, count)
+		}
+	}
+}